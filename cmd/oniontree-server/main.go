@@ -0,0 +1,28 @@
+// Command oniontree-server serves an OnionTree's operations over HTTP so
+// that multiple clients (web UIs, monitors, bots) can share one canonical
+// tree without importing the Go library or racing on filesystem writes.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/oniontree-org/go-oniontree/server"
+)
+
+func main() {
+	var (
+		dir  = flag.String("dir", ".", "path to the OnionTree directory")
+		addr = flag.String("addr", ":8080", "address to listen on")
+	)
+	flag.Parse()
+
+	ot := oniontree.New(*dir)
+
+	log.Printf("serving %s on %s", *dir, *addr)
+	if err := http.ListenAndServe(*addr, server.New(ot)); err != nil {
+		log.Fatal(err)
+	}
+}
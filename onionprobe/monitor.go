@@ -0,0 +1,245 @@
+// Package onionprobe probes the URLs of every service in an OnionTree
+// through Tor on a schedule and reports their reachability as events,
+// turning a static registry of onion services into a live liveness dataset.
+package onionprobe
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+)
+
+// Config controls how a Monitor probes service URLs.
+type Config struct {
+	// SOCKSProxyAddr is the address of the Tor SOCKS5 proxy used to dial
+	// .onion URLs, e.g. "127.0.0.1:9050".
+	SOCKSProxyAddr string
+
+	// Interval is the base delay between successful probes of a URL.
+	Interval time.Duration
+
+	// Jitter is the maximum random delay added on top of Interval (and
+	// of the backoff below) so that URLs are not probed in lockstep.
+	Jitter time.Duration
+
+	// Concurrency is the maximum number of URLs probed at once.
+	Concurrency int
+
+	// MaxBackoff caps the exponential backoff applied to a URL for as
+	// long as it stays offline.
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig returns sane defaults for probing onion services through a
+// local Tor daemon.
+func DefaultConfig() Config {
+	return Config{
+		SOCKSProxyAddr: "127.0.0.1:9050",
+		Interval:       5 * time.Minute,
+		Jitter:         30 * time.Second,
+		Concurrency:    4,
+		MaxBackoff:     1 * time.Hour,
+	}
+}
+
+// Monitor periodically dials every URL of every service in an OnionTree
+// through Tor and reports their reachability as events.
+type Monitor struct {
+	ot     *oniontree.OnionTree
+	cfg    Config
+	client *http.Client
+	store  *store
+
+	mu     sync.Mutex
+	states map[string]*urlState
+}
+
+type urlState struct {
+	backoff time.Duration
+	nextRun time.Time
+}
+
+// NewMonitor returns a Monitor that probes the services in ot using cfg. The
+// last-known status of every URL is persisted under
+// ot.Dir()+"/.oniontree-status.yaml" so that restarts do not lose history.
+func NewMonitor(ot *oniontree.OnionTree, cfg Config) (*Monitor, error) {
+	dialer, err := proxy.SOCKS5("tcp", cfg.SOCKSProxyAddr, nil, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := openStore(ot.Dir() + "/.oniontree-status.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	return &Monitor{
+		ot:  ot,
+		cfg: cfg,
+		client: &http.Client{
+			Transport: &http.Transport{Dial: dialer.Dial},
+			Timeout:   30 * time.Second,
+		},
+		store:  st,
+		states: make(map[string]*urlState),
+	}, nil
+}
+
+// Run probes every service URL on a schedule until ctx is done, emitting
+// ServiceURLOnline and ServiceURLOffline events on eventCh.
+func (m *Monitor) Run(ctx context.Context, eventCh chan<- events.Event) error {
+	sem := make(chan struct{}, m.cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	probeAll := func() {
+		ids, err := m.ot.ListServices()
+		if err != nil {
+			return
+		}
+		for _, id := range ids {
+			service, err := m.ot.GetService(id)
+			if err != nil {
+				continue
+			}
+			for _, url := range service.URLs {
+				if !m.due(id, url) {
+					continue
+				}
+
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(id, url string) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					m.probe(ctx, id, url, eventCh)
+				}(id, url)
+			}
+		}
+	}
+
+	probeAll()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case <-ticker.C:
+			probeAll()
+		}
+	}
+}
+
+func (m *Monitor) due(serviceID, url string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[serviceID+" "+url]
+	if !ok {
+		return true
+	}
+	return !time.Now().Before(state.nextRun)
+}
+
+func (m *Monitor) schedule(serviceID, url string, online bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := serviceID + " " + url
+	state, ok := m.states[key]
+	if !ok {
+		state = &urlState{}
+		m.states[key] = state
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(m.cfg.Jitter) + 1))
+
+	if online {
+		state.backoff = 0
+		state.nextRun = time.Now().Add(m.cfg.Interval + jitter)
+		return
+	}
+
+	if state.backoff == 0 {
+		state.backoff = m.cfg.Interval
+	} else {
+		state.backoff *= 2
+	}
+	if state.backoff > m.cfg.MaxBackoff {
+		state.backoff = m.cfg.MaxBackoff
+	}
+	state.nextRun = time.Now().Add(state.backoff + jitter)
+}
+
+func (m *Monitor) probe(ctx context.Context, serviceID, url string, eventCh chan<- events.Event) {
+	if !validateOnionAddress(url) {
+		m.offline(serviceID, url, status{Err: "invalid onion address", CheckedAt: time.Now()}, eventCh, ctx)
+		return
+	}
+
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var resp *http.Response
+	if err == nil {
+		resp, err = m.client.Do(req)
+		if resp != nil {
+			defer resp.Body.Close()
+		}
+	}
+
+	if err != nil {
+		m.offline(serviceID, url, status{Err: err.Error(), CheckedAt: time.Now()}, eventCh, ctx)
+		return
+	}
+
+	latency := time.Since(start)
+	tlsValid := resp.TLS != nil && len(resp.TLS.VerifiedChains) > 0
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		m.offline(serviceID, url, status{
+			Err:        fmt.Sprintf("unexpected HTTP status %d", resp.StatusCode),
+			Latency:    latency,
+			StatusCode: resp.StatusCode,
+			TLSValid:   tlsValid,
+			CheckedAt:  time.Now(),
+		}, eventCh, ctx)
+		return
+	}
+
+	m.schedule(serviceID, url, true)
+	m.store.set(serviceID, url, status{
+		Online:     true,
+		Latency:    latency,
+		StatusCode: resp.StatusCode,
+		TLSValid:   tlsValid,
+		CheckedAt:  time.Now(),
+	})
+	select {
+	case eventCh <- events.ServiceURLOnline{ID: serviceID, URL: url, Latency: latency}:
+	case <-ctx.Done():
+	}
+}
+
+func (m *Monitor) offline(serviceID, url string, st status, eventCh chan<- events.Event, ctx context.Context) {
+	m.schedule(serviceID, url, false)
+	m.store.set(serviceID, url, st)
+	select {
+	case eventCh <- events.ServiceURLOffline{ID: serviceID, URL: url, Err: st.Err}:
+	case <-ctx.Done():
+	}
+}
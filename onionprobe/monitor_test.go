@@ -0,0 +1,54 @@
+package onionprobe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMonitor(t *testing.T) *Monitor {
+	return &Monitor{
+		cfg: Config{
+			Interval:   time.Minute,
+			Jitter:     0,
+			MaxBackoff: 4 * time.Minute,
+		},
+		states: make(map[string]*urlState),
+	}
+}
+
+func TestMonitor_ScheduleBackoff(t *testing.T) {
+	m := testMonitor(t)
+	serviceID, url := "dummyservice", "http://dummy.onion"
+
+	assert.True(t, m.due(serviceID, url), "a URL probed for the first time must be due")
+
+	m.schedule(serviceID, url, false)
+	firstBackoff := m.states[serviceID+" "+url].backoff
+	assert.Equal(t, m.cfg.Interval, firstBackoff)
+	assert.False(t, m.due(serviceID, url), "a just-scheduled URL must not be due again immediately")
+
+	m.schedule(serviceID, url, false)
+	secondBackoff := m.states[serviceID+" "+url].backoff
+	assert.Equal(t, 2*firstBackoff, secondBackoff, "backoff must double on consecutive failures")
+
+	m.schedule(serviceID, url, false)
+	m.schedule(serviceID, url, false)
+	cappedBackoff := m.states[serviceID+" "+url].backoff
+	assert.Equal(t, m.cfg.MaxBackoff, cappedBackoff, "backoff must not exceed MaxBackoff")
+}
+
+func TestMonitor_ScheduleResetsBackoffWhenOnline(t *testing.T) {
+	m := testMonitor(t)
+	serviceID, url := "dummyservice", "http://dummy.onion"
+
+	m.schedule(serviceID, url, false)
+	m.schedule(serviceID, url, false)
+	assert.NotZero(t, m.states[serviceID+" "+url].backoff)
+
+	m.schedule(serviceID, url, true)
+	state := m.states[serviceID+" "+url]
+	assert.Zero(t, state.backoff, "a successful probe must reset the backoff")
+	assert.False(t, state.nextRun.Before(time.Now()), "a successful probe must schedule the next run in the future")
+}
@@ -0,0 +1,68 @@
+package onionprobe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-yaml/yaml"
+)
+
+// status is the last-known reachability of a single service URL.
+type status struct {
+	Online     bool          `yaml:"online"`
+	Err        string        `yaml:"err,omitempty"`
+	Latency    time.Duration `yaml:"latency"`
+	StatusCode int           `yaml:"status_code,omitempty"`
+	TLSValid   bool          `yaml:"tls_valid,omitempty"`
+	CheckedAt  time.Time     `yaml:"checked_at"`
+}
+
+// store is a small on-disk record of the last-known status of every probed
+// URL, keyed by "serviceID url", so that a restart does not lose history.
+type store struct {
+	path string
+
+	mu   sync.Mutex
+	data map[string]status
+}
+
+func openStore(path string) (*store, error) {
+	s := &store{path: path, data: make(map[string]status)}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, &s.data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *store) set(serviceID, url string, st status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[serviceID+" "+url] = st
+	_ = s.save()
+}
+
+func (s *store) save() error {
+	b, err := yaml.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.path, b, 0644)
+}
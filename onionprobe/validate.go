@@ -0,0 +1,34 @@
+package onionprobe
+
+import (
+	"net/url"
+	"regexp"
+)
+
+var (
+	onionV3Addr = regexp.MustCompile(`^[a-z2-7]{56}\.onion$`)
+	onionV2Addr = regexp.MustCompile(`^[a-z2-7]{16}\.onion$`)
+)
+
+// validateOnionAddress reports whether rawURL's host is either not a .onion
+// address (e.g. a clearnet mirror, which this check does not apply to) or a
+// well-formed v2/v3 onion address. It returns false for malformed .onion
+// hosts, such as a wrong-length address or a typo'd phishing lookalike.
+func validateOnionAddress(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host := u.Hostname()
+	if !hasOnionSuffix(host) {
+		return true
+	}
+
+	return onionV3Addr.MatchString(host) || onionV2Addr.MatchString(host)
+}
+
+func hasOnionSuffix(host string) bool {
+	const suffix = ".onion"
+	return len(host) > len(suffix) && host[len(host)-len(suffix):] == suffix
+}
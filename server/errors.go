@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/oniontree-org/go-oniontree"
+)
+
+// apiError is the structured JSON body returned for non-2xx responses.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// writeError maps known oniontree errors to a specific HTTP status code (409,
+// 404, 400 or 422; several errors share one) and writes a structured JSON
+// error body. Unrecognized errors map to 500.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch err.(type) {
+	case *oniontree.ErrIdExists:
+		status = http.StatusConflict
+	case *oniontree.ErrIdNotExists:
+		status = http.StatusNotFound
+	case *oniontree.ErrInvalidID:
+		status = http.StatusBadRequest
+	case *oniontree.ErrInvalidTagName:
+		status = http.StatusBadRequest
+	case *oniontree.ErrUnknownURL:
+		status = http.StatusUnprocessableEntity
+	default:
+		switch err {
+		case oniontree.ErrNoSignature, oniontree.ErrKeyNotTrusted:
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	writeJSON(w, status, apiError{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
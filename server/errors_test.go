@@ -0,0 +1,41 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"id exists", &oniontree.ErrIdExists{}, http.StatusConflict},
+		{"id not exists", &oniontree.ErrIdNotExists{}, http.StatusNotFound},
+		{"invalid id", &oniontree.ErrInvalidID{}, http.StatusBadRequest},
+		{"invalid tag name", &oniontree.ErrInvalidTagName{}, http.StatusBadRequest},
+		{"unknown url", &oniontree.ErrUnknownURL{URL: "http://example.onion"}, http.StatusUnprocessableEntity},
+		{"no signature", oniontree.ErrNoSignature, http.StatusUnprocessableEntity},
+		{"key not trusted", oniontree.ErrKeyNotTrusted, http.StatusUnprocessableEntity},
+		{"unrecognized", assert.AnError, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeError(rec, tt.err)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			var body apiError
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.err.Error(), body.Error)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+)
+
+// handleEvents serves GET /events as a server-sent-events stream of the
+// tree's add/remove/tag transitions, backed by a single shared Watcher. A
+// client reconnecting after a dropped connection can pass either the
+// Last-Event-ID header or a since_seq query parameter to replay the events
+// it missed before the stream resumes live.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, apiError{Error: "streaming unsupported"})
+		return
+	}
+
+	offsetStr := r.Header.Get("Last-Event-ID")
+	if offsetStr == "" {
+		offsetStr = r.URL.Query().Get("since_seq")
+	}
+	offset, _ := strconv.ParseUint(offsetStr, 10, 64)
+
+	s.hub.start(s.ctx)
+
+	// Subscribe before reading the backlog so that no live event landing
+	// while the backlog is read can be missed; duplicates of already
+	// replayed events are filtered out by sequence number below.
+	sub := s.hub.subscribe()
+	defer s.hub.unsubscribe(sub)
+
+	past, err := s.hub.replay(offset)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	lastSeq := offset
+	for _, event := range past {
+		writeEvent(w, event)
+		lastSeq = event.Sequence()
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-sub:
+			if event.Sequence() <= lastSeq {
+				continue
+			}
+			lastSeq = event.Sequence()
+			writeEvent(w, event)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event events.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.Sequence(), data)
+}
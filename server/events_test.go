@@ -0,0 +1,50 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleEvents(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/events", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		s.handleEvents(rec, req)
+		close(done)
+	}()
+
+	// Give handleEvents time to subscribe before the write that follows, so
+	// it is delivered live rather than missed entirely.
+	time.Sleep(50 * time.Millisecond)
+
+	assert.NoError(t, s.ot.AddService(oniontree.NewService("dummyservice")))
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(rec.Body.String(), "dummyservice")
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	var sawID bool
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "id: ") {
+			sawID = true
+		}
+	}
+	assert.True(t, sawID, "SSE output must include an id: line")
+}
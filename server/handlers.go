@@ -0,0 +1,236 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/oniontree-org/go-oniontree"
+	"golang.org/x/crypto/openpgp"
+)
+
+// handleServices serves GET /services?tag=... and POST /services, which
+// creates a service from the ID in its body. To create a service with a
+// specific ID, POST to /services/{id} instead.
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listServices(w, r)
+	case http.MethodPost:
+		s.createService(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) listServices(w http.ResponseWriter, r *http.Request) {
+	tag := r.URL.Query().Get("tag")
+
+	var ids []string
+	var err error
+	if tag != "" {
+		ids, err = s.ot.ListServicesWithTag(oniontree.Tag(tag))
+	} else {
+		ids, err = s.ot.ListServices()
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ids)
+}
+
+func (s *Server) createService(w http.ResponseWriter, r *http.Request) {
+	service := &oniontree.Service{}
+	if err := json.NewDecoder(r.Body).Decode(service); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ot.AddService(service); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, service)
+}
+
+// handleService serves GET/POST/PUT/DELETE /services/{id} and
+// POST /services/{id}/tags.
+func (s *Server) handleService(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/services/")
+	parts := strings.SplitN(path, "/", 2)
+	serviceID := parts[0]
+
+	if len(parts) == 2 && parts[1] == "tags" {
+		s.handleServiceTags(w, r, serviceID)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getService(w, serviceID)
+	case http.MethodPost:
+		s.createServiceWithID(w, r, serviceID)
+	case http.MethodPut:
+		s.updateService(w, r, serviceID)
+	case http.MethodDelete:
+		s.deleteService(w, serviceID)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) createServiceWithID(w http.ResponseWriter, r *http.Request, serviceID string) {
+	service := &oniontree.Service{}
+	if err := json.NewDecoder(r.Body).Decode(service); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+	service.ID = serviceID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ot.AddService(service); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, service)
+}
+
+func (s *Server) getService(w http.ResponseWriter, serviceID string) {
+	service, err := s.ot.GetService(serviceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, service)
+}
+
+func (s *Server) updateService(w http.ResponseWriter, r *http.Request, serviceID string) {
+	service := &oniontree.Service{}
+	if err := json.NewDecoder(r.Body).Decode(service); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+	service.ID = serviceID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ot.UpdateService(service); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, service)
+}
+
+func (s *Server) deleteService(w http.ResponseWriter, serviceID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ot.RemoveService(serviceID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleServiceTags(w http.ResponseWriter, r *http.Request, serviceID string) {
+	switch r.Method {
+	case http.MethodGet:
+		tags, err := s.ot.ListServiceTags(serviceID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, tags)
+	case http.MethodPost:
+		var tags []oniontree.Tag
+		if err := json.NewDecoder(r.Body).Decode(&tags); err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if err := s.ot.TagService(serviceID, tags); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, tags)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleTags serves GET /tags.
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	tags, err := s.ot.ListTags()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// handleVerify serves POST /verify?service={id}, verifying the clearsigned
+// body posted by the client against the service's registered public keys.
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	serviceID := r.URL.Query().Get("service")
+	if serviceID == "" {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "missing service query parameter"})
+		return
+	}
+
+	clearsigned, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	urls, entity, err := s.ot.VerifySignedURLs(serviceID, clearsigned)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		URLs    []string `json:"urls"`
+		KeyID   string   `json:"key_id"`
+		UserIDs []string `json:"user_ids"`
+	}{
+		URLs:    urls,
+		KeyID:   entity.PrimaryKey.KeyIdString(),
+		UserIDs: identityNames(entity),
+	})
+}
+
+func identityNames(entity *openpgp.Entity) []string {
+	names := make([]string, 0, len(entity.Identities))
+	for name := range entity.Identities {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) (*Server, func()) {
+	dir, err := ioutil.TempDir("", "go-oniontree")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ot := oniontree.New(dir)
+	if err := ot.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(ot)
+	return s, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestHandleServices(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	body, _ := json.Marshal(oniontree.NewService("dummyservice"))
+	req := httptest.NewRequest(http.MethodPost, "/services", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.handleServices(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/services", nil)
+	rec = httptest.NewRecorder()
+	s.handleServices(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var ids []string
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ids))
+	assert.Equal(t, []string{"dummyservice"}, ids)
+}
+
+func TestHandleService(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	body, _ := json.Marshal(oniontree.NewService("ignored-id"))
+	req := httptest.NewRequest(http.MethodPost, "/services/dummyservice", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created *oniontree.Service
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "dummyservice", created.ID, "the path id must win over the body's id")
+
+	req = httptest.NewRequest(http.MethodGet, "/services/dummyservice", nil)
+	rec = httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	service := oniontree.NewService("dummyservice")
+	service.Name = "Dummy Service"
+	body, _ = json.Marshal(service)
+	req = httptest.NewRequest(http.MethodPut, "/services/dummyservice", bytes.NewReader(body))
+	rec = httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/services/dummyservice", nil)
+	rec = httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/services/dummyservice", nil)
+	rec = httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleServiceTags(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	service := oniontree.NewService("dummyservice")
+	assert.NoError(t, s.ot.AddService(service))
+
+	body, _ := json.Marshal([]oniontree.Tag{"link_list"})
+	req := httptest.NewRequest(http.MethodPost, "/services/dummyservice/tags", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/services/dummyservice/tags", nil)
+	rec = httptest.NewRecorder()
+	s.handleService(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var tags []oniontree.Tag
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tags))
+	assert.Equal(t, []oniontree.Tag{"link_list"}, tags)
+}
+
+func TestHandleTags(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	service := oniontree.NewService("dummyservice")
+	assert.NoError(t, s.ot.AddService(service))
+	assert.NoError(t, s.ot.TagService("dummyservice", []oniontree.Tag{"link_list"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	rec := httptest.NewRecorder()
+	s.handleTags(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var tags []oniontree.Tag
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tags))
+	assert.Equal(t, []oniontree.Tag{"link_list"}, tags)
+}
+
+func TestHandleVerifyMissingService(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodPost, "/verify", nil)
+	rec := httptest.NewRecorder()
+	s.handleVerify(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleVerifyUnknownService(t *testing.T) {
+	s, cleanup := newTestServer(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodPost, "/verify?service=dummyservice", bytes.NewReader([]byte("not signed")))
+	rec := httptest.NewRecorder()
+	s.handleVerify(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
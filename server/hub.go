@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/oniontree-org/go-oniontree/watcher"
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+)
+
+// hub runs a single Watcher and fans its events out to every subscribed
+// /events client, so that concurrent requests do not each start their own
+// fsnotify watcher and race on the tree's append-only event log.
+type hub struct {
+	ot *oniontree.OnionTree
+
+	startOnce sync.Once
+
+	mu          sync.Mutex
+	subscribers map[chan events.Event]bool
+}
+
+func newHub(ot *oniontree.OnionTree) *hub {
+	return &hub{ot: ot, subscribers: make(map[chan events.Event]bool)}
+}
+
+// start begins watching ot in the background the first time it is called.
+// Subsequent calls are no-ops; ctx should outlive any individual request.
+func (h *hub) start(ctx context.Context) {
+	h.startOnce.Do(func() {
+		go h.run(ctx)
+	})
+}
+
+// run watches ot until ctx is done, restarting with a growing backoff if
+// Watch ever returns an error (e.g. the fsnotify watch itself failing)
+// instead of silently stopping event delivery for the rest of the
+// process's life.
+func (h *hub) run(ctx context.Context) {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for ctx.Err() == nil {
+		eventCh := make(chan events.Event)
+		forwarded := make(chan struct{})
+		go func() {
+			defer close(forwarded)
+			for event := range eventCh {
+				h.broadcast(event)
+			}
+		}()
+
+		err := watcher.NewWatcher(h.ot).Watch(ctx, eventCh)
+		close(eventCh)
+		<-forwarded
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("server: /events watcher failed, restarting in %s: %v", backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (h *hub) broadcast(event events.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		select {
+		case sub <- event:
+		default:
+			// Slow subscriber; drop rather than block the watcher.
+		}
+	}
+}
+
+// subscribe registers a new channel that receives every event broadcast
+// from here on. Call unsubscribe when done.
+func (h *hub) subscribe() chan events.Event {
+	ch := make(chan events.Event, 16)
+
+	h.mu.Lock()
+	h.subscribers[ch] = true
+	h.mu.Unlock()
+
+	return ch
+}
+
+func (h *hub) unsubscribe(ch chan events.Event) {
+	h.mu.Lock()
+	delete(h.subscribers, ch)
+	h.mu.Unlock()
+}
+
+// replay returns every event recorded in the tree's persistent log after
+// offset.
+func (h *hub) replay(offset uint64) ([]events.Event, error) {
+	return watcher.NewWatcher(h.ot).ReadFrom(offset)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_BroadcastFansOutToEverySubscriber(t *testing.T) {
+	h := newHub(nil)
+
+	subA := h.subscribe()
+	subB := h.subscribe()
+	defer h.unsubscribe(subA)
+	defer h.unsubscribe(subB)
+
+	event := events.ServiceAdded{ID: "dummyservice", Seq: 1}
+	h.broadcast(event)
+
+	assertReceives(t, subA, event)
+	assertReceives(t, subB, event)
+}
+
+func TestHub_UnsubscribeStopsDelivery(t *testing.T) {
+	h := newHub(nil)
+
+	sub := h.subscribe()
+	h.unsubscribe(sub)
+
+	h.broadcast(events.ServiceAdded{ID: "dummyservice", Seq: 1})
+
+	select {
+	case event, ok := <-sub:
+		t.Fatalf("unsubscribed channel received an event: %v (open=%v)", event, ok)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestHub_BroadcastDropsOnSlowSubscriber(t *testing.T) {
+	h := newHub(nil)
+
+	sub := h.subscribe()
+	defer h.unsubscribe(sub)
+
+	// Fill the subscriber's buffer, then broadcast once more: broadcast must
+	// not block waiting on a slow reader.
+	for i := 0; i < cap(sub)+1; i++ {
+		h.broadcast(events.ServiceAdded{ID: "dummyservice", Seq: uint64(i)})
+	}
+}
+
+func assertReceives(t *testing.T, ch <-chan events.Event, want events.Event) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		assert.Equal(t, want, got)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
@@ -0,0 +1,62 @@
+// Package server exposes the operations of *oniontree.OnionTree over a
+// JSON/HTTP API so that multiple clients (web UIs, monitors, bots) can share
+// a single tree without importing the Go library or racing on filesystem
+// writes.
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/oniontree-org/go-oniontree"
+)
+
+// Server wraps an *oniontree.OnionTree and serves its operations over HTTP.
+// All writes are serialized through mu so that concurrent requests cannot
+// race on the underlying filesystem.
+type Server struct {
+	ot *oniontree.OnionTree
+	mu sync.Mutex
+
+	mux *http.ServeMux
+	hub *hub
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New returns a Server backed by ot. Call Init on ot beforehand if the tree
+// does not exist yet.
+func New(ot *oniontree.OnionTree) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Server{
+		ot:     ot,
+		mux:    http.NewServeMux(),
+		hub:    newHub(ot),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	s.routes()
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close stops the background watcher backing the /events endpoint.
+func (s *Server) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("/services", s.handleServices)
+	s.mux.HandleFunc("/services/", s.handleService)
+	s.mux.HandleFunc("/tags", s.handleTags)
+	s.mux.HandleFunc("/verify", s.handleVerify)
+	s.mux.HandleFunc("/events", s.handleEvents)
+}
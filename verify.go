@@ -0,0 +1,92 @@
+package oniontree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+// ErrNoSignature is returned when the provided data is not a valid
+// clearsigned PGP message.
+var ErrNoSignature = errors.New("no PGP signature found")
+
+// ErrKeyNotTrusted is returned when a clearsigned message was signed by a key
+// that is not among the service's registered public keys.
+var ErrKeyNotTrusted = errors.New("signing key is not trusted by the service")
+
+// ErrUnknownURL is returned when a clearsigned message lists a URL that is
+// not declared in the service's URLs.
+type ErrUnknownURL struct {
+	URL string
+}
+
+func (e *ErrUnknownURL) Error() string {
+	return fmt.Sprintf("url %q is not declared by the service", e.URL)
+}
+
+// Verify decodes clearsigned, checks it was signed by one of the service's
+// registered public keys and that every URL in the signed payload is
+// declared in s.URLs. On success it returns the signed URLs and the entity
+// that produced the signature.
+func (s *Service) Verify(clearsigned []byte) ([]string, *openpgp.Entity, error) {
+	block, _ := clearsign.Decode(clearsigned)
+	if block == nil {
+		return nil, nil, ErrNoSignature
+	}
+
+	entity, err := openpgp.CheckDetachedSignature(s.PublicKeys, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body)
+	if err != nil {
+		return nil, nil, ErrKeyNotTrusted
+	}
+
+	urls := strings.Fields(string(block.Plaintext))
+
+	known := make(map[string]bool, len(s.URLs))
+	for _, u := range s.URLs {
+		known[u] = true
+	}
+	for _, u := range urls {
+		if !known[u] {
+			return nil, nil, &ErrUnknownURL{URL: u}
+		}
+	}
+
+	return urls, entity, nil
+}
+
+// VerifySignedURLs looks up serviceID and verifies clearsigned against the
+// service's registered public keys. See Service.Verify for details.
+func (ot *OnionTree) VerifySignedURLs(serviceID string, clearsigned []byte) ([]string, *openpgp.Entity, error) {
+	service, err := ot.GetService(serviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.Verify(clearsigned)
+}
+
+// SignURLs clearsigns serviceID's declared URLs, one per line, with entity's
+// private key. It is the inverse of Service.Verify.
+func (ot *OnionTree) SignURLs(serviceID string, entity *openpgp.Entity) ([]byte, error) {
+	service, err := ot.GetService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w, err := clearsign.Encode(&buf, entity.PrivateKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte(strings.Join(service.URLs, "\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,170 @@
+package oniontree_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+const signedURLsFixture = `-----BEGIN PGP SIGNED MESSAGE-----
+Hash: SHA512
+
+http://onions53ehmf4q75.onion
+https://oniontree.org
+-----BEGIN PGP SIGNATURE-----
+
+iQIzBAEBCgAdFiEE8B/tR5eVVMktn1ay5LbKxJskKkQFAl3mwUoACgkQ5LbKxJsk
+KkS7kw/+KYFiTv7Z0vAxU07tSdEE/w5JGCnhBKHwgoxuM0fa09bknDMyLPLi9nIz
+HnJu8+f5+yktbsObX4Hr8jCs8NK9LKBc75uORmlqcilzmPTHQ0suBnURsP8+iPLi
+qsDB5kkLzEX1lLfVaSWyIMy8UfXyWeJvDWagQUfP3w6kTS3NvjobIcS5ZyEApzxn
+/d9wyEhI1uKp0ai5koLMTHQQu02pIFiykH0n8OiroAjgPZpb1HzQvj/3Ylny4Yey
+qRsxSWX0YueGLUMuCrAEjBemooguoEuN8bCjvWpN+rqO0TBWr9KWRFdDw9q42mR7
+ju/myQUlKnxNxD4VqhEcczz7BeqxnB60SGd1/IJvNDVEc0aNqt963A81r0DFhOaR
+Z1ItUYT4Jpd5xPtHWONmQdVr8Wa45g+XhHmGiTKVAwHA8vQLCOlnZji03ElVq5T+
+/Zjs+x2QnUvzut5ohjRpjaoxKk2dhc+D1gAuQ/xzyKT2679zrJkaUdIR0ycijbJ6
+togmI1x+j4a8qCPmmJNYGYicf7h618VmGMnWElKfCvBOWne8uIZyWTttivKCiR8j
+KFnmLRTsnTsoIJ1lDQ/xqXAPzUIu/TP0Omkjk5+UpofqBZEfzR9tPJFut0MMLXn1
+C9eumAqFSLZeMtTdG7LzXo1Iby2MnKjWowvifyhUOh3ohl0bLu8=
+=ETay
+-----END PGP SIGNATURE-----`
+
+func TestOnionTree_VerifySignedURLs(t *testing.T) {
+	ot, cleanup := copyOnionTree(t)
+	defer cleanup()
+
+	// Declare every URL listed in the fixture, so that none is unknown.
+	service, err := ot.GetService("oniontree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.URLs = []string{"http://onions53ehmf4q75.onion", "https://oniontree.org"}
+	if err := ot.UpdateService(service); err != nil {
+		t.Fatal(err)
+	}
+
+	urls, entity, err := ot.VerifySignedURLs("oniontree", []byte(signedURLsFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{"http://onions53ehmf4q75.onion", "https://oniontree.org"}, urls)
+	assert.Equal(t, "E4B6CAC49B242A44", entity.PrimaryKey.KeyIdString())
+}
+
+// generateTestEntity returns a fresh PGP entity and its ASCII-armored public
+// key block, so tests can register it as a service's trusted key without
+// depending on the testdata fixture's hardcoded key.
+func generateTestEntity(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("Test User", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return entity, buf.String()
+}
+
+func TestOnionTree_SignURLsVerifyRoundTrip(t *testing.T) {
+	ot, cleanup := newOnionTree(t)
+	defer cleanup()
+	if err := ot.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	entity, armoredKey := generateTestEntity(t)
+
+	service := oniontree.NewService("dummyservice")
+	service.URLs = []string{"http://onions53ehmf4q75.onion", "https://oniontree.org"}
+	service.PublicKeys = []*oniontree.PublicKey{
+		{
+			ID:     entity.PrimaryKey.KeyIdString(),
+			UserID: "Test User <test@example.com>",
+			Value:  armoredKey,
+		},
+	}
+	if err := ot.AddService(service); err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := ot.SignURLs("dummyservice", entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	urls, verifiedEntity, err := ot.VerifySignedURLs("dummyservice", signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, service.URLs, urls)
+	assert.Equal(t, entity.PrimaryKey.KeyIdString(), verifiedEntity.PrimaryKey.KeyIdString())
+}
+
+func TestOnionTree_VerifyErrorNoSignature(t *testing.T) {
+	service := oniontree.NewService("dummyservice")
+
+	_, _, err := service.Verify([]byte("not a clearsigned message"))
+	assert.Equal(t, oniontree.ErrNoSignature, err)
+}
+
+func TestOnionTree_VerifyErrorKeyNotTrusted(t *testing.T) {
+	ot, cleanup := newOnionTree(t)
+	defer cleanup()
+	if err := ot.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	trusted, trustedArmoredKey := generateTestEntity(t)
+	untrusted, _ := generateTestEntity(t)
+
+	service := oniontree.NewService("dummyservice")
+	service.URLs = []string{"http://onions53ehmf4q75.onion"}
+	service.PublicKeys = []*oniontree.PublicKey{
+		{ID: trusted.PrimaryKey.KeyIdString(), Value: trustedArmoredKey},
+	}
+	if err := ot.AddService(service); err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := ot.SignURLs("dummyservice", untrusted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = ot.VerifySignedURLs("dummyservice", signed)
+	assert.Equal(t, oniontree.ErrKeyNotTrusted, err)
+}
+
+func TestOnionTree_VerifySignedURLsErrorUnknownURL(t *testing.T) {
+	ot, cleanup := copyOnionTree(t)
+	defer cleanup()
+
+	service, err := ot.GetService("oniontree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.URLs = []string{"https://oniontree.org"}
+	if err := ot.UpdateService(service); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = ot.VerifySignedURLs("oniontree", []byte(signedURLsFixture))
+	if _, ok := err.(*oniontree.ErrUnknownURL); !ok {
+		t.Fatalf("expected *oniontree.ErrUnknownURL, got %v", err)
+	}
+}
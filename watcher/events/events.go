@@ -0,0 +1,86 @@
+// Package events defines the events emitted by the watcher package and its
+// consumers as an OnionTree changes on disk or its services' reachability
+// changes.
+package events
+
+// Event is implemented by every event type in this package. Sequence
+// identifies the event's position in the watcher's persistent event log, so
+// that a consumer replaying history can pick up where it left off. Events
+// that are not logged there (onionprobe's reachability events) report 0.
+type Event interface {
+	isEvent()
+	Sequence() uint64
+}
+
+// ServiceAdded is emitted when a new service file appears in the tree.
+type ServiceAdded struct {
+	ID  string
+	Seq uint64
+}
+
+func (ServiceAdded) isEvent()           {}
+func (e ServiceAdded) Sequence() uint64 { return e.Seq }
+
+// ServiceUpdated is emitted when an existing service file is modified.
+type ServiceUpdated struct {
+	ID  string
+	Seq uint64
+}
+
+func (ServiceUpdated) isEvent()           {}
+func (e ServiceUpdated) Sequence() uint64 { return e.Seq }
+
+// ServiceRemoved is emitted when a service file is deleted from the tree.
+type ServiceRemoved struct {
+	ID  string
+	Seq uint64
+}
+
+func (ServiceRemoved) isEvent()           {}
+func (e ServiceRemoved) Sequence() uint64 { return e.Seq }
+
+// ServiceTagged is emitted when a service is linked under a tag.
+type ServiceTagged struct {
+	ID  string
+	Tag string
+	Seq uint64
+}
+
+func (ServiceTagged) isEvent()           {}
+func (e ServiceTagged) Sequence() uint64 { return e.Seq }
+
+// ServiceUntagged is emitted when a service is unlinked from a tag.
+type ServiceUntagged struct {
+	ID  string
+	Tag string
+	Seq uint64
+}
+
+func (ServiceUntagged) isEvent()           {}
+func (e ServiceUntagged) Sequence() uint64 { return e.Seq }
+
+// WithSequence returns a copy of event stamped with seq. It is used by the
+// watcher's persistent log to assign each tree-transition event its position
+// in the log; onionprobe's reachability events are not logged there and are
+// left untouched.
+func WithSequence(event Event, seq uint64) Event {
+	switch e := event.(type) {
+	case ServiceAdded:
+		e.Seq = seq
+		return e
+	case ServiceUpdated:
+		e.Seq = seq
+		return e
+	case ServiceRemoved:
+		e.Seq = seq
+		return e
+	case ServiceTagged:
+		e.Seq = seq
+		return e
+	case ServiceUntagged:
+		e.Seq = seq
+		return e
+	default:
+		return event
+	}
+}
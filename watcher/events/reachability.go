@@ -0,0 +1,27 @@
+package events
+
+import "time"
+
+// ServiceURLOnline is emitted by onionprobe when a service URL responds to a
+// probe.
+type ServiceURLOnline struct {
+	ID      string
+	URL     string
+	Latency time.Duration
+	Seq     uint64
+}
+
+func (ServiceURLOnline) isEvent()           {}
+func (e ServiceURLOnline) Sequence() uint64 { return e.Seq }
+
+// ServiceURLOffline is emitted by onionprobe when a service URL fails to
+// respond to a probe.
+type ServiceURLOffline struct {
+	ID  string
+	URL string
+	Err string
+	Seq uint64
+}
+
+func (ServiceURLOffline) isEvent()           {}
+func (e ServiceURLOffline) Sequence() uint64 { return e.Seq }
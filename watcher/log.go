@@ -0,0 +1,213 @@
+package watcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+)
+
+// eventLogPath is where the watcher's append-only event log is stored,
+// relative to the OnionTree directory. It sits beside the .oniontree marker
+// file rather than under it, as that marker is a file and not a directory.
+const eventLogPath = ".oniontree-events.log"
+
+// loggedEvent is the on-disk JSON-lines representation of an event: its
+// concrete type name and logging time alongside its JSON-encoded payload, so
+// that heterogeneous events.Event values can be round-tripped.
+type loggedEvent struct {
+	Type     string          `json:"type"`
+	LoggedAt time.Time       `json:"logged_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+type timestampedEvent struct {
+	events.Event
+	loggedAt time.Time
+}
+
+// eventLog is an append-only, JSON-lines log of every event the watcher has
+// ever emitted. It lets a consumer that crashed or restarted replay history
+// instead of only observing events while the watcher is alive.
+type eventLog struct {
+	mu      sync.Mutex
+	file    *os.File
+	nextSeq uint64
+}
+
+func openEventLog(dir string) (*eventLog, error) {
+	path := filepath.Join(dir, eventLogPath)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &eventLog{file: f}
+
+	entries, err := l.readAll()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	l.nextSeq = uint64(len(entries)) + 1
+
+	return l, nil
+}
+
+// append assigns event the next sequence number, persists it and returns
+// that sequence number.
+func (l *eventLog) append(event events.Event) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seq := l.nextSeq
+	event = events.WithSequence(event, seq)
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	line, err := json.Marshal(loggedEvent{
+		Type:     typeName(event),
+		LoggedAt: time.Now(),
+		Data:     data,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return 0, err
+	}
+
+	l.nextSeq++
+	return seq, nil
+}
+
+// readFrom returns every logged event with a sequence number greater than
+// offset, in log order.
+func (l *eventLog) readFrom(offset uint64) ([]events.Event, error) {
+	entries, err := l.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]events.Event, 0, len(entries))
+	for _, e := range entries {
+		if e.Sequence() > offset {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// readSince returns every logged event recorded at or after t, in log order.
+func (l *eventLog) readSince(t time.Time) ([]events.Event, error) {
+	timestamped, err := l.readAllTimestamped()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]events.Event, 0, len(timestamped))
+	for _, e := range timestamped {
+		if !e.loggedAt.Before(t) {
+			out = append(out, e.Event)
+		}
+	}
+	return out, nil
+}
+
+func (l *eventLog) readAll() ([]events.Event, error) {
+	timestamped, err := l.readAllTimestamped()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]events.Event, 0, len(timestamped))
+	for _, e := range timestamped {
+		out = append(out, e.Event)
+	}
+	return out, nil
+}
+
+func (l *eventLog) readAllTimestamped() ([]timestampedEvent, error) {
+	if _, err := l.file.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	var out []timestampedEvent
+	scanner := bufio.NewScanner(l.file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var le loggedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &le); err != nil {
+			return nil, err
+		}
+
+		event, err := decodeEvent(le)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, timestampedEvent{Event: event, loggedAt: le.LoggedAt})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := l.file.Seek(0, 2); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func typeName(event events.Event) string {
+	switch event.(type) {
+	case events.ServiceAdded:
+		return "ServiceAdded"
+	case events.ServiceUpdated:
+		return "ServiceUpdated"
+	case events.ServiceRemoved:
+		return "ServiceRemoved"
+	case events.ServiceTagged:
+		return "ServiceTagged"
+	case events.ServiceUntagged:
+		return "ServiceUntagged"
+	default:
+		return ""
+	}
+}
+
+func decodeEvent(le loggedEvent) (events.Event, error) {
+	switch le.Type {
+	case "ServiceAdded":
+		var e events.ServiceAdded
+		return e, json.Unmarshal(le.Data, &e)
+	case "ServiceUpdated":
+		var e events.ServiceUpdated
+		return e, json.Unmarshal(le.Data, &e)
+	case "ServiceRemoved":
+		var e events.ServiceRemoved
+		return e, json.Unmarshal(le.Data, &e)
+	case "ServiceTagged":
+		var e events.ServiceTagged
+		return e, json.Unmarshal(le.Data, &e)
+	case "ServiceUntagged":
+		var e events.ServiceUntagged
+		return e, json.Unmarshal(le.Data, &e)
+	default:
+		return nil, fmt.Errorf("watcher: unknown logged event type %q", le.Type)
+	}
+}
@@ -0,0 +1,56 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+)
+
+// ReadFrom returns every event recorded in the tree's persistent log after
+// offset, without watching for further live events. Callers that need to
+// fan a single Watch out to several replay consumers (such as the server
+// package's /events endpoint) can use this instead of WatchFrom to avoid
+// running one fsnotify watcher per consumer.
+func (w *Watcher) ReadFrom(offset uint64) ([]events.Event, error) {
+	log, err := openEventLog(w.ot.Dir())
+	if err != nil {
+		return nil, err
+	}
+	defer log.file.Close()
+
+	return log.readFrom(offset)
+}
+
+// WatchFrom replays every event recorded in the tree's persistent log after
+// offset, then keeps watching live like Watch. A consumer can persist the
+// Sequence of the last event it processed and resume from there after a
+// crash or restart without missing any add/remove/tag transition.
+func (w *Watcher) WatchFrom(ctx context.Context, offset uint64, eventCh chan<- events.Event) error {
+	past, err := w.ReadFrom(offset)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range past {
+		select {
+		case eventCh <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return w.Watch(ctx, eventCh)
+}
+
+// Since returns every event recorded in the tree's persistent log at or
+// after t.
+func (w *Watcher) Since(t time.Time) ([]events.Event, error) {
+	log, err := openEventLog(w.ot.Dir())
+	if err != nil {
+		return nil, err
+	}
+	defer log.file.Close()
+
+	return log.readSince(t)
+}
@@ -0,0 +1,125 @@
+// Package watcher watches an OnionTree's directory tree for service
+// add/update/remove and tag/untag transitions and emits them as
+// watcher/events.Event values.
+package watcher
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/oniontree-org/go-oniontree/watcher/events"
+)
+
+// Watcher watches an OnionTree for add/update/remove/tag/untag transitions.
+// Every event it observes is appended to the tree's persistent event log
+// before being delivered, so that WatchFrom can later replay it.
+type Watcher struct {
+	ot *oniontree.OnionTree
+}
+
+// NewWatcher returns a Watcher for ot.
+func NewWatcher(ot *oniontree.OnionTree) *Watcher {
+	return &Watcher{ot: ot}
+}
+
+// Watch watches ot for changes until ctx is done, sending every observed
+// event to eventCh in order.
+func (w *Watcher) Watch(ctx context.Context, eventCh chan<- events.Event) error {
+	log, err := openEventLog(w.ot.Dir())
+	if err != nil {
+		return err
+	}
+	defer log.file.Close()
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	if err := fsw.Add(w.ot.UnsortedDir()); err != nil {
+		return err
+	}
+	if err := fsw.Add(w.ot.TaggedDir()); err != nil {
+		return err
+	}
+
+	tagDirs := make(map[string]bool)
+	tagEntries, err := filepath.Glob(w.ot.TaggedDir() + "/*")
+	if err != nil {
+		return err
+	}
+	for _, dir := range tagEntries {
+		if err := fsw.Add(dir); err != nil {
+			return err
+		}
+		tagDirs[dir] = true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-fsw.Errors:
+			return err
+		case fsEvent := <-fsw.Events:
+			event, ok := w.translate(fsw, fsEvent, tagDirs)
+			if !ok {
+				continue
+			}
+
+			seq, err := log.append(event)
+			if err != nil {
+				return err
+			}
+			event = events.WithSequence(event, seq)
+
+			select {
+			case eventCh <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// translate maps a raw filesystem event into a watcher event. The second
+// return value is false for filesystem events that do not correspond to one
+// of our transitions (e.g. newly discovered tag directories).
+func (w *Watcher) translate(fsw *fsnotify.Watcher, fsEvent fsnotify.Event, tagDirs map[string]bool) (events.Event, bool) {
+	dir := filepath.Dir(fsEvent.Name)
+	base := filepath.Base(fsEvent.Name)
+	id := strings.TrimSuffix(base, filepath.Ext(base))
+
+	switch {
+	case dir == w.ot.UnsortedDir():
+		switch {
+		case fsEvent.Op&fsnotify.Create != 0:
+			return events.ServiceAdded{ID: id}, true
+		case fsEvent.Op&fsnotify.Write != 0:
+			return events.ServiceUpdated{ID: id}, true
+		case fsEvent.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+			return events.ServiceRemoved{ID: id}, true
+		}
+	case dir == w.ot.TaggedDir():
+		if fsEvent.Op&fsnotify.Create != 0 && !tagDirs[fsEvent.Name] {
+			if err := fsw.Add(fsEvent.Name); err == nil {
+				tagDirs[fsEvent.Name] = true
+			}
+		}
+	case tagDirs[dir]:
+		tag := filepath.Base(dir)
+		switch {
+		case fsEvent.Op&fsnotify.Create != 0:
+			return events.ServiceTagged{ID: id, Tag: tag}, true
+		case fsEvent.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+			return events.ServiceUntagged{ID: id, Tag: tag}, true
+		}
+	}
+
+	return nil, false
+}
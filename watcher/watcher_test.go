@@ -3,8 +3,8 @@ package watcher
 import (
 	"context"
 	"fmt"
-	"github.com/onionltd/go-oniontree"
-	"github.com/onionltd/go-oniontree/watcher/events"
+	"github.com/oniontree-org/go-oniontree"
+	"github.com/oniontree-org/go-oniontree/watcher/events"
 	"github.com/otiai10/copy"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
@@ -42,30 +42,33 @@ func mustEvent(t *testing.T, event events.Event, eventCh <-chan events.Event) {
 
 func mustAddService(t *testing.T, ot *oniontree.OnionTree, eventCh <-chan events.Event) {
 	serviceID := "testservice"
-	serviceData := oniontree.NewService(serviceID)
-	if err := ot.AddService(serviceID, serviceData); err != nil {
+	service := oniontree.NewService(serviceID)
+	if err := ot.AddService(service); err != nil {
 		t.Fatal(err)
 	}
 
 	mustEvent(t, events.ServiceAdded{
-		ID: serviceID,
+		ID:  serviceID,
+		Seq: 1,
 	}, eventCh)
 
 	mustEvent(t, events.ServiceUpdated{
-		ID: serviceID,
+		ID:  serviceID,
+		Seq: 2,
 	}, eventCh)
 }
 
 func mustTagService(t *testing.T, ot *oniontree.OnionTree, eventCh <-chan events.Event) {
 	serviceID := "testservice"
-	tagName := "test"
-	if err := ot.TagService(serviceID, []string{tagName}); err != nil {
+	tag := oniontree.Tag("test")
+	if err := ot.TagService(serviceID, []oniontree.Tag{tag}); err != nil {
 		t.Fatal(err)
 	}
 
 	mustEvent(t, events.ServiceTagged{
 		ID:  serviceID,
-		Tag: tagName,
+		Tag: tag.String(),
+		Seq: 3,
 	}, eventCh)
 }
 
@@ -78,10 +81,12 @@ func mustRemoveService(t *testing.T, ot *oniontree.OnionTree, eventCh <-chan eve
 	mustEvent(t, events.ServiceUntagged{
 		ID:  serviceID,
 		Tag: "test",
+		Seq: 4,
 	}, eventCh)
 
 	mustEvent(t, events.ServiceRemoved{
-		ID: serviceID,
+		ID:  serviceID,
+		Seq: 5,
 	}, eventCh)
 }
 